@@ -0,0 +1,99 @@
+package goflux
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Router is the top-level entry point for registering routes. It owns the
+// root of the radix tree along with router-wide settings such as Mutable.
+type Router struct {
+	tree *node
+
+	// mutable, when true, makes Handle replace an existing handler for the
+	// same method and path instead of panicking.
+	mutable bool
+
+	// paramsPool recycles *Params between requests, each pre-sized to the
+	// tree's current maxParams so a lookup for a parameterized route
+	// doesn't need to grow the slice via append.
+	paramsPool sync.Pool
+}
+
+// New creates an empty Router.
+func New() *Router {
+	r := &Router{tree: &node{}}
+	r.paramsPool.New = func() interface{} {
+		p := make(Params, 0, r.tree.maxParams)
+		return &p
+	}
+	return r
+}
+
+// Mutable toggles whether Handle replaces an already-registered handler for
+// a method/path instead of panicking. This is useful for hot-reloading
+// tests, plugin systems, and dev-time route swapping.
+func (r *Router) Mutable(mutable bool) {
+	r.mutable = mutable
+}
+
+// Handle registers handler for the given method and path, honoring the
+// router's current Mutable setting.
+func (r *Router) Handle(method, path string, handler HandlerFunc) {
+	r.tree.addRoute(path, method, handler, r.mutable)
+}
+
+// HandleMutable registers handler for the given method and path, replacing
+// any existing handler for that method/path regardless of the router's
+// Mutable setting.
+func (r *Router) HandleMutable(method, path string, handler HandlerFunc) {
+	r.tree.addRoute(path, method, handler, true)
+}
+
+// Lookup looks up the handler for method and path. tsr reports whether
+// appending or trimming a trailing slash from path would have matched
+// instead, so the caller can issue a redirect rather than a 404. Unlike
+// ServeHTTP, Lookup hands the returned Params to the caller, so it builds
+// a fresh slice rather than borrowing one from the pool.
+func (r *Router) Lookup(method, path string) (handler HandlerFunc, params Params, tsr bool) {
+	p := make(Params, 0, r.tree.maxParams)
+	handler, tsr = r.tree.getValue(path, method, &p)
+	return handler, p, tsr
+}
+
+// FindCaseInsensitivePath looks up path ignoring case and, when
+// fixTrailingSlash is true, a missing or extra trailing slash too. It
+// returns the path as registered in the tree, suitable for a redirect.
+func (r *Router) FindCaseInsensitivePath(path string, fixTrailingSlash bool) (string, bool) {
+	return r.tree.FindCaseInsensitivePath(path, fixTrailingSlash)
+}
+
+// ServeHTTP implements http.Handler. It borrows a pre-sized *Params from the
+// pool for the lookup, dispatches to the matched handler, and returns the
+// Params to the pool once the handler has run so the hot, parameterized-route
+// path allocates nothing per request.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	p := r.paramsPool.Get().(*Params)
+	*p = (*p)[:0]
+
+	handler, tsr := r.tree.getValue(req.URL.Path, req.Method, p)
+	if handler != nil {
+		handler(w, req, *p)
+		r.paramsPool.Put(p)
+		return
+	}
+	r.paramsPool.Put(p)
+
+	if tsr {
+		fixedPath := req.URL.Path
+		if fixedPath[len(fixedPath)-1] == '/' {
+			fixedPath = fixedPath[:len(fixedPath)-1]
+		} else {
+			fixedPath = fixedPath + "/"
+		}
+		http.Redirect(w, req, fixedPath, http.StatusMovedPermanently)
+		return
+	}
+
+	http.NotFound(w, req)
+}
@@ -0,0 +1,74 @@
+package goflux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_HandleDuplicatePanicsByDefault(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for duplicate route registration without Mutable")
+		}
+	}()
+
+	r := New()
+	r.Handle("GET", "/users", testHandler)
+	r.Handle("GET", "/users", testHandler) // Should panic
+}
+
+func TestRouter_MutableReplacesHandler(t *testing.T) {
+	first := func(w http.ResponseWriter, r *http.Request, p Params) { w.Write([]byte("first")) }
+	second := func(w http.ResponseWriter, r *http.Request, p Params) { w.Write([]byte("second")) }
+
+	r := New()
+	r.Handle("GET", "/users", first)
+
+	r.Mutable(true)
+	r.Handle("GET", "/users", second) // should replace, not panic
+
+	handler, _, _ := lookupTest(r.tree, "/users", "GET")
+	if handler == nil {
+		t.Fatal("Expected to find handler for /users GET")
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, nil, nil)
+	if rec.Body.String() != "second" {
+		t.Errorf("Expected replaced handler to run, got body %q", rec.Body.String())
+	}
+}
+
+func BenchmarkRouter_ServeHTTPParamRoute(b *testing.B) {
+	r := New()
+	r.Handle("GET", "/posts/:slug/comments/:commentId", func(w http.ResponseWriter, req *http.Request, p Params) {})
+
+	req := httptest.NewRequest("GET", "/posts/hello-world/comments/456", nil)
+	rec := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(rec, req)
+	}
+}
+
+func TestRouter_HandleMutableIgnoresMutableSetting(t *testing.T) {
+	first := func(w http.ResponseWriter, r *http.Request, p Params) { w.Write([]byte("first")) }
+	second := func(w http.ResponseWriter, r *http.Request, p Params) { w.Write([]byte("second")) }
+
+	r := New() // Mutable left false
+	r.Handle("GET", "/users", first)
+	r.HandleMutable("GET", "/users", second) // should still replace
+
+	handler, _, _ := lookupTest(r.tree, "/users", "GET")
+	if handler == nil {
+		t.Fatal("Expected to find handler for /users GET")
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, nil, nil)
+	if rec.Body.String() != "second" {
+		t.Errorf("Expected replaced handler to run, got body %q", rec.Body.String())
+	}
+}
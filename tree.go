@@ -2,6 +2,9 @@ package goflux
 
 import (
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
 )
 
 // HandlerFunc defines the handler used by GoFlux
@@ -39,15 +42,43 @@ const (
 
 // node represents a single node in the radix tree
 type node struct {
-	path      string                 // the path segment this node represents
-	handlers  map[string]HandlerFunc // maps HTTP method -> handler function
-	nType     nodeType               // what type of node is this
-	children  []*node                // child nodes
-	wildChild bool                   // true if any child is param or catchAll
+	path       string                 // the path segment this node represents
+	handlers   map[string]HandlerFunc // maps HTTP method -> handler function
+	nType      nodeType               // what type of node is this
+	children   []*node                // child nodes, kept sorted by descending priority
+	wildChild  bool                   // true if any child is param or catchAll
+	priority   uint32                 // number of routes that pass through this node
+	maxParams  uint16                 // max number of params in any route under this node
+	constraint *regexp.Regexp         // optional regex the param/catchAll value must match, e.g. :id(\d+)
 }
 
-// addRoute adds a new route to the tree
-func (n *node) addRoute(path string, method string, handler HandlerFunc) {
+// skippedNode remembers a wildcard branch that getValue passed over in favor
+// of a static child, so it can backtrack to it if the static branch turns
+// out to be a dead end.
+type skippedNode struct {
+	path      string
+	node      *node
+	paramsLen int
+}
+
+// addRoute adds a new route to the tree. When mutable is true, registering a
+// route for a method/path that already has a handler replaces it instead of
+// panicking.
+//
+// Every node visited while inserting path has its priority bumped and its
+// children re-sorted by descending priority afterwards, so a later getValue
+// walk checks the hottest branches first. Each node's maxParams is also kept
+// up to date so a Router can pre-size a Params slice for the deepest route
+// reachable from that node.
+func (n *node) addRoute(path string, method string, handler HandlerFunc, mutable bool) {
+	n.priority++
+	defer n.sortChildren()
+
+	oldMaxParams := n.maxParams
+	if c := countParams(path); c > n.maxParams {
+		n.maxParams = c
+	}
+
 	// If this is an empty tree
 	if len(n.path) == 0 && len(n.children) == 0 {
 		n.nType = root
@@ -63,31 +94,32 @@ func (n *node) addRoute(path string, method string, handler HandlerFunc) {
 			// Split into static part and wildcard part
 			if wildcardIndex > 0 {
 				n.path = path[:wildcardIndex]
+				n.insertChild(path[wildcardIndex:], method, handler)
+				return
+			}
 
-				// Create wildcard child
-				child := &node{
-					path:     wildcard,
-					nType:    param,
-					handlers: make(map[string]HandlerFunc),
-				}
-
-				if wildcard[0] == '*' {
-					child.nType = catchAll
-				}
+			// Wildcard at the start
+			name, pattern := splitParamConstraint(wildcard)
+			n.path = name
+			n.nType = param
+			if name[0] == '*' {
+				n.nType = catchAll
+			}
+			n.constraint = compileConstraint(pattern)
 
-				child.handlers[method] = handler
-				n.children = append(n.children, child)
-				n.wildChild = true
-			} else {
-				// Wildcard at the start
-				n.path = wildcard
-				n.nType = param
-				if wildcard[0] == '*' {
-					n.nType = catchAll
-				}
+			leftover := path[len(wildcard):]
+			if leftover == "" {
 				n.handlers = make(map[string]HandlerFunc)
 				n.handlers[method] = handler
+				return
 			}
+
+			// More segments follow this wildcard (e.g. ":name/*action"),
+			// so keep building the tree underneath it.
+			if n.nType == catchAll {
+				panic("catch-all routes are only allowed at the end of the path, in path '" + path + "'")
+			}
+			n.insertChild(leftover, method, handler)
 		} else {
 			// No wildcard, simple static route
 			n.path = path
@@ -106,7 +138,7 @@ func (n *node) addRoute(path string, method string, handler HandlerFunc) {
 			n.handlers = make(map[string]HandlerFunc)
 		}
 		// Check if handler already exists for this method
-		if _, exists := n.handlers[method]; exists {
+		if _, exists := n.handlers[method]; exists && !mutable {
 			panic("handler already registered for path '" + path + "' and method '" + method + "'")
 		}
 		n.handlers[method] = handler
@@ -117,14 +149,18 @@ func (n *node) addRoute(path string, method string, handler HandlerFunc) {
 	if commonPrefix == len(path) && commonPrefix < len(n.path) {
 		// Split the node
 		child := &node{
-			path:     n.path[commonPrefix:],
-			handlers: n.handlers,
-			children: n.children,
-			nType:    static,
+			path:      n.path[commonPrefix:],
+			handlers:  n.handlers,
+			children:  n.children,
+			nType:     static,
+			wildChild: n.wildChild,
+			priority:  n.priority - 1,
+			maxParams: oldMaxParams,
 		}
 
 		n.path = path
 		n.children = []*node{child}
+		n.wildChild = false
 		n.handlers = make(map[string]HandlerFunc)
 		n.handlers[method] = handler
 		return
@@ -134,15 +170,19 @@ func (n *node) addRoute(path string, method string, handler HandlerFunc) {
 	if commonPrefix < len(n.path) {
 		// Create a child with the remaining part of current path
 		child := &node{
-			path:     n.path[commonPrefix:],
-			handlers: n.handlers,
-			children: n.children,
-			nType:    static,
+			path:      n.path[commonPrefix:],
+			handlers:  n.handlers,
+			children:  n.children,
+			nType:     static,
+			wildChild: n.wildChild,
+			priority:  n.priority - 1,
+			maxParams: oldMaxParams,
 		}
 
 		// Update current node to only have the common prefix
 		n.path = n.path[:commonPrefix]
 		n.children = []*node{child}
+		n.wildChild = false
 		n.handlers = nil
 	}
 
@@ -154,77 +194,107 @@ func (n *node) addRoute(path string, method string, handler HandlerFunc) {
 		for _, child := range n.children {
 			if child.path[0] == remainingPath[0] {
 				// Recursively add to this child
-				child.addRoute(remainingPath, method, handler)
+				child.addRoute(remainingPath, method, handler, mutable)
 				return
 			}
 		}
 
-		// No matching child, check if new path has wildcards
-		wildcard, wildcardIndex, valid := findWildcard(remainingPath)
+		// No matching child, insert a new static/param/catchAll subtree
+		n.insertChild(remainingPath, method, handler)
+	}
+}
 
-		if wildcardIndex >= 0 {
-			// Path has a wildcard, need special handling
-			if !valid {
-				panic("invalid wildcard in path: " + remainingPath)
-			}
+// insertChild builds the subtree for remainingPath under n and attaches it,
+// recursing as needed so that a single pattern can carry more than one
+// wildcard segment (e.g. "/:name/*action"). n itself keeps its existing
+// path/children untouched; only new children are appended.
+func (n *node) insertChild(remainingPath, method string, handler HandlerFunc) {
+	wildcard, wildcardIndex, valid := findWildcard(remainingPath)
 
-			// If wildcard doesn't start at beginning, we need to add a static node first
-			if wildcardIndex > 0 {
-				// Create a static child for the part before wildcard
-				staticChild := &node{
-					path:  remainingPath[:wildcardIndex],
-					nType: static,
-				}
-				n.children = append(n.children, staticChild)
+	if wildcardIndex < 0 {
+		// No wildcard left, so the rest of the path is a plain static child
+		child := &node{
+			path:      remainingPath,
+			handlers:  make(map[string]HandlerFunc),
+			nType:     static,
+			priority:  1,
+			maxParams: countParams(remainingPath),
+		}
+		child.handlers[method] = handler
+		n.children = append(n.children, child)
+		return
+	}
 
-				// Now continue from the static child
-				remainingPath = remainingPath[wildcardIndex:]
-				wildcard, _, _ = findWildcard(remainingPath)
+	if !valid {
+		panic("invalid wildcard in path: " + remainingPath)
+	}
 
-				// Create wildcard as child of the static node
-				wildcardChild := &node{
-					path:  wildcard,
-					nType: param,
-				}
+	if wildcardIndex > 0 {
+		// Static prefix before the wildcard gets its own node
+		staticChild := &node{
+			path:      remainingPath[:wildcardIndex],
+			nType:     static,
+			priority:  1,
+			maxParams: countParams(remainingPath[wildcardIndex:]),
+		}
+		n.children = append(n.children, staticChild)
 
-				if wildcard[0] == '*' {
-					wildcardChild.nType = catchAll
-				}
+		n = staticChild
+		remainingPath = remainingPath[wildcardIndex:]
+		wildcard, _, _ = findWildcard(remainingPath)
+	}
 
-				wildcardChild.handlers = make(map[string]HandlerFunc)
-				wildcardChild.handlers[method] = handler
-				staticChild.children = append(staticChild.children, wildcardChild)
-				staticChild.wildChild = true
-				return
-			}
-			// Create wildcard child
-			child := &node{
-				path:  wildcard,
-				nType: param, // Will be set to catchAll if it starts with *
-			}
+	name, pattern := splitParamConstraint(wildcard)
+	wildcardChild := &node{
+		path:       name,
+		nType:      param, // Will be set to catchAll if it starts with *
+		priority:   1,
+		maxParams:  countParams(remainingPath),
+		constraint: compileConstraint(pattern),
+	}
+	if name[0] == '*' {
+		wildcardChild.nType = catchAll
+	}
+	n.children = append(n.children, wildcardChild)
+	n.wildChild = true
 
-			// Check if it's a catch-all (*filepath)
-			if wildcard[0] == '*' {
-				child.nType = catchAll
-			}
+	leftover := remainingPath[len(wildcard):]
+	if leftover == "" {
+		wildcardChild.handlers = make(map[string]HandlerFunc)
+		wildcardChild.handlers[method] = handler
+		return
+	}
 
-			child.handlers = make(map[string]HandlerFunc)
-			child.handlers[method] = handler
-			n.children = append(n.children, child)
-			n.wildChild = true
-		} else {
-			// No wildcard, regular static child
-			newChild := &node{
-				path:     remainingPath,
-				handlers: make(map[string]HandlerFunc),
-				nType:    static,
-			}
-			newChild.handlers[method] = handler
-			n.children = append(n.children, newChild)
+	if wildcardChild.nType == catchAll {
+		panic("catch-all routes are only allowed at the end of the path, in path '" + remainingPath + "'")
+	}
+
+	// More segments follow this wildcard, keep building underneath it
+	wildcardChild.insertChild(leftover, method, handler)
+}
+
+// countParams reports how many wildcard segments (:param or *catchAll)
+// appear in path.
+func countParams(path string) uint16 {
+	var n uint16
+	for {
+		wildcard, i, _ := findWildcard(path)
+		if i < 0 {
+			return n
 		}
+		n++
+		path = path[i+len(wildcard):]
 	}
 }
 
+// sortChildren re-sorts n's children by descending priority so the most
+// heavily-traveled branch is checked first during a lookup walk.
+func (n *node) sortChildren() {
+	sort.SliceStable(n.children, func(i, j int) bool {
+		return n.children[i].priority > n.children[j].priority
+	})
+}
+
 // longestCommonPrefix finds the length of the common prefix between two strings
 func longestCommonPrefix(a, b string) int {
 	i := 0
@@ -243,105 +313,164 @@ func min(a, b int) int {
 	return b
 }
 
-// getValue searches the tree for a matching route
-func (n *node) getValue(path string, method string) (HandlerFunc, Params) {
-	var params Params
+// getValue searches the tree for a matching route. Any :param/*catchAll
+// values encountered along the way are appended to *p, which a caller such
+// as Router typically gets from a sync.Pool sized via the tree's maxParams
+// so a lookup for a parameterized route doesn't need to allocate. If no
+// exact match is found, getValue also reports via tsr whether appending or
+// trimming a trailing slash from path would resolve to a registered
+// handler, so the caller can redirect rather than 404.
+func (n *node) getValue(path string, method string, p *Params) (handler HandlerFunc, tsr bool) {
+	savedLen := len(*p)
+
+	if handler = n.lookup(path, method, p); handler != nil {
+		return handler, false
+	}
+	*p = (*p)[:savedLen]
+
+	if path == "" {
+		return nil, false
+	}
+
+	var altPath string
+	if path[len(path)-1] == '/' {
+		altPath = path[:len(path)-1]
+	} else {
+		altPath = path + "/"
+	}
+
+	if altHandler := n.lookup(altPath, method, p); altHandler != nil {
+		tsr = true
+	}
+	*p = (*p)[:savedLen]
+	return nil, tsr
+}
+
+// lookup walks the tree for an exact match of path and method, appending any
+// :param/*catchAll values to *p. Static children are always tried before a
+// sibling :param or *catchAll child; if the static branch turns out to be a
+// dead end, the walk backtracks and retries the wildcard branch instead of
+// giving up.
+func (n *node) lookup(path string, method string, p *Params) HandlerFunc {
+	var skipped []skippedNode
+
+	// When true, the prefix/static-child matching below is skipped because
+	// we just backtracked to retry the wildcard branch of a skipped node.
+	skipStatic := false
 
-	// Walk through the tree
 walk:
 	for {
-		// If the path is longer than this node's path
-		if len(path) > len(n.path) {
-			// Check if the node's path is a prefix of the search path
-			if path[:len(n.path)] == n.path {
-				path = path[len(n.path):] // Remove the matched prefix
-
-				// If this node has wildcard children, check them
-				if n.wildChild {
-					for _, child := range n.children {
-						// Handle parameter nodes (:id)
-						if child.nType == param {
-							// Find the end of the parameter value
-							end := 0
-							for end < len(path) && path[end] != '/' {
-								end++
-							}
-
-							// Extract parameter name (remove the :)
-							paramName := child.path[1:]
-							paramValue := path[:end]
-
-							// Add to params
-							params = append(params, Param{
-								Key:   paramName,
-								Value: paramValue,
-							})
-
-							// Continue with remaining path
-							if end < len(path) {
-								path = path[end:]
-								n = child
-								continue walk
-							}
-
-							// End of path, check if handler exists
-							if handler, ok := child.handlers[method]; ok {
-								return handler, params
-							}
-							return nil, nil
-						}
+		if !skipStatic {
+			// A param node's own path (e.g. ":slug") is just its name, not
+			// literal route text, so its value was already consumed by the
+			// wildcard handling below that led us here; nothing to strip.
+			if n.nType == static || n.nType == root {
+				if len(path) < len(n.path) || path[:len(n.path)] != n.path {
+					goto backtrack
+				}
+				path = path[len(n.path):]
+			}
 
-						// Handle catch-all nodes (*filepath)
-						if child.nType == catchAll {
-							// Extract parameter name (remove the *)
-							paramName := child.path[1:]
-
-							// Rest of path is the value
-							params = append(params, Param{
-								Key:   paramName,
-								Value: path,
-							})
-
-							if handler, ok := child.handlers[method]; ok {
-								return handler, params
-							}
-							return nil, nil
-						}
+			if path == "" {
+				if handler, ok := n.handlers[method]; ok {
+					return handler
+				}
+				goto backtrack
+			}
+
+			// Static children always take priority over a wildcard sibling
+			for _, child := range n.children {
+				if child.nType == static && len(child.path) > 0 && child.path[0] == path[0] {
+					if n.wildChild {
+						// Remember the wildcard branch in case this static
+						// branch doesn't pan out.
+						skipped = append(skipped, skippedNode{
+							path:      path,
+							node:      n,
+							paramsLen: len(*p),
+						})
 					}
+					n = child
+					continue walk
 				}
+			}
+		}
+		skipStatic = false
+
+		// No static match (or we're deliberately retrying the wildcard
+		// branch): fall back to this node's :param or *catchAll child.
+		if n.wildChild && path != "" {
+			for _, child := range n.children {
+				if child.nType == param {
+					// Find the end of the segment (up to the next '/')
+					segEnd := 0
+					for segEnd < len(path) && path[segEnd] != '/' {
+						segEnd++
+					}
+
+					consumed, ok := matchConstraint(child.constraint, path[:segEnd], len(child.children) > 0)
+					if !ok {
+						// Doesn't satisfy this param's constraint; try the
+						// next sibling instead of binding it.
+						continue
+					}
+					end := consumed
 
-				// Try to find a matching static child
-				for _, child := range n.children {
-					if child.nType == static && len(child.path) > 0 && child.path[0] == path[0] {
+					*p = append(*p, Param{
+						Key:   child.path[1:],
+						Value: path[:end],
+					})
+
+					if end < len(path) {
+						path = path[end:]
 						n = child
 						continue walk
 					}
-				}
 
-				// No matching child found
-				return nil, nil
-			}
-		}
+					if handler, ok := child.handlers[method]; ok {
+						return handler
+					}
+					*p = (*p)[:len(*p)-1]
+				} else if child.nType == catchAll {
+					if _, ok := matchConstraint(child.constraint, path, false); !ok {
+						continue
+					}
 
-		// Check if we found an exact match
-		if path == n.path {
-			if handler, ok := n.handlers[method]; ok {
-				return handler, params
+					*p = append(*p, Param{
+						Key:   child.path[1:],
+						Value: path,
+					})
+
+					if handler, ok := child.handlers[method]; ok {
+						return handler
+					}
+					*p = (*p)[:len(*p)-1]
+				}
 			}
-			// Path matches but method doesn't
-			return nil, nil
 		}
 
-		// No match found
-		return nil, nil
+	backtrack:
+		// This branch was a dead end; retry the last skipped wildcard, if any
+		if len(skipped) == 0 {
+			return nil
+		}
+		last := skipped[len(skipped)-1]
+		skipped = skipped[:len(skipped)-1]
+		path = last.path
+		n = last.node
+		*p = (*p)[:last.paramsLen]
+		skipStatic = true
 	}
 }
 
-// findWildcard finds the first wildcard segment (:param or *catchall) in the path
-// Returns: wildcard string, index where it starts, and whether it's valid
+// findWildcard finds the first wildcard segment (:param or *catchall) in the
+// path, optionally followed by an inline regex constraint in parens, e.g.
+// ":id(\d+)" or ":name([a-z]+)". Returns: wildcard string (name plus any
+// constraint), index where it starts, and whether it's valid.
 func findWildcard(path string) (wildcard string, i int, valid bool) {
 	// Find the first : or *
-	for start, c := range []byte(path) {
+	for start := 0; start < len(path); start++ {
+		c := path[start]
 		if c != ':' && c != '*' {
 			continue
 		}
@@ -349,16 +478,236 @@ func findWildcard(path string) (wildcard string, i int, valid bool) {
 		// Found a wildcard
 		valid = true
 
-		// Find where the wildcard ends (at / or end of string)
-		for end, c := range []byte(path[start+1:]) {
-			if c == '/' {
-				return path[start : start+1+end], start, valid
+		end := start + 1
+		for end < len(path) && isParamNameChar(path[end]) {
+			end++
+		}
+
+		if end < len(path) && path[end] == '(' {
+			// A constraint follows the name; scan to its matching ')',
+			// tracking depth since the pattern itself may contain groups.
+			depth := 1
+			end++
+			for end < len(path) && depth > 0 {
+				switch path[end] {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+				end++
+			}
+			if depth != 0 {
+				return path[start:], start, false
 			}
+			return path[start:end], start, valid
 		}
 
-		// Wildcard goes to end of path
-		return path[start:], start, valid
+		// No constraint: the wildcard runs to the next '/' or end of string.
+		for end < len(path) && path[end] != '/' {
+			end++
+		}
+		return path[start:end], start, valid
 	}
 
 	return "", -1, false
 }
+
+// isParamNameChar reports whether c can appear in a :param or *catchAll name.
+func isParamNameChar(c byte) bool {
+	return c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// splitParamConstraint separates a wildcard's name from its inline regex
+// constraint, if any: splitParamConstraint(":id(\\d+)") returns (":id",
+// "\\d+"); splitParamConstraint(":id") returns (":id", "").
+func splitParamConstraint(wildcard string) (name string, pattern string) {
+	if idx := strings.IndexByte(wildcard, '('); idx >= 0 && wildcard[len(wildcard)-1] == ')' {
+		return wildcard[:idx], wildcard[idx+1 : len(wildcard)-1]
+	}
+	return wildcard, ""
+}
+
+// compileConstraint compiles pattern anchored to the start of the value (so
+// a match always begins where the param does), or returns nil if pattern is
+// empty. It panics if pattern isn't a valid regular expression.
+func compileConstraint(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")")
+	if err != nil {
+		panic("invalid parameter constraint '" + pattern + "': " + err.Error())
+	}
+	return re
+}
+
+// matchConstraint checks segment (the text up to the next '/' or end of
+// path) against a param's constraint. When the node has further children,
+// e.g. a literal "." before another param as in ":name([a-z]+).:ext", only
+// the matched prefix needs to satisfy the constraint and the rest of the
+// segment is left for those children to consume; a leaf param must match
+// the constraint across the whole segment. It reports the length of
+// segment that was consumed and whether the constraint was satisfied.
+func matchConstraint(constraint *regexp.Regexp, segment string, hasChildren bool) (consumed int, ok bool) {
+	if constraint == nil {
+		return len(segment), true
+	}
+	loc := constraint.FindStringIndex(segment)
+	if loc == nil || loc[0] != 0 {
+		return 0, false
+	}
+	if !hasChildren && loc[1] != len(segment) {
+		return 0, false
+	}
+	return loc[1], true
+}
+
+// CleanPath normalizes a request path by collapsing repeated slashes and
+// resolving "." and ".." segments, the same way path.Clean does, while
+// preserving a trailing slash and leaving any ":param"/"*catchAll" segments
+// untouched (they're just ordinary path text from CleanPath's point of view).
+func CleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	trailingSlash := len(p) > 1 && p[len(p)-1] == '/'
+
+	segments := strings.Split(p, "/")
+	cleaned := make([]string, 0, len(segments))
+
+	for _, seg := range segments {
+		switch seg {
+		case "", ".":
+			// Drop empty segments (collapses duplicate slashes) and "." segments
+		case "..":
+			// Pop the last segment, if there is one to pop
+			if len(cleaned) > 0 {
+				cleaned = cleaned[:len(cleaned)-1]
+			}
+		default:
+			cleaned = append(cleaned, seg)
+		}
+	}
+
+	result := "/" + strings.Join(cleaned, "/")
+	if trailingSlash && result != "/" {
+		result += "/"
+	}
+	return result
+}
+
+// FindCaseInsensitivePath looks up path ignoring case and, when
+// fixTrailingSlash is true, a missing or extra trailing slash too. It
+// returns the path as it is actually registered in the tree, so a client
+// that requested "/Users/123/" can be redirected to the canonical
+// "/users/123".
+func (n *node) FindCaseInsensitivePath(path string, fixTrailingSlash bool) (string, bool) {
+	ciPath, found := n.findCaseInsensitivePath(CleanPath(path), make([]byte, 0, len(path)+1), fixTrailingSlash)
+	return string(ciPath), found
+}
+
+// findCaseInsensitivePath is the recursive worker behind FindCaseInsensitivePath.
+// ciPath accumulates the canonically-cased path as the tree is descended.
+func (n *node) findCaseInsensitivePath(path string, ciPath []byte, fixTrailingSlash bool) ([]byte, bool) {
+	// A param/catchAll node's own path (e.g. ":id") is just its name, not
+	// literal route text; its value was already consumed by the caller
+	// before recursing here, so there's nothing left to strip.
+	if n.nType == static || n.nType == root {
+		if len(n.path) > 0 {
+			if len(path) < len(n.path) || !strings.EqualFold(path[:len(n.path)], n.path) {
+				// The request may lack only the trailing slash that ends this node's path
+				if fixTrailingSlash && len(path)+1 == len(n.path) && n.path[len(path)] == '/' &&
+					strings.EqualFold(path, n.path[:len(path)]) && len(n.handlers) > 0 {
+					return append(ciPath, n.path...), true
+				}
+				return ciPath, false
+			}
+			ciPath = append(ciPath, n.path...) // keep the registered casing, not the request's
+			path = path[len(n.path):]
+		}
+	}
+
+	if path == "" {
+		if len(n.handlers) > 0 {
+			return ciPath, true
+		}
+
+		// No handler on this exact node; a child that's just "/" might be
+		// the intended route if the caller is tolerating a missing slash.
+		if fixTrailingSlash {
+			for _, child := range n.children {
+				if child.path == "/" && len(child.handlers) > 0 {
+					return append(ciPath, '/'), true
+				}
+			}
+		}
+		return ciPath, false
+	}
+
+	// Static children take priority over a wildcard sibling, same as lookup
+	for _, child := range n.children {
+		if child.nType == static {
+			if out, ok := child.findCaseInsensitivePath(path, append([]byte{}, ciPath...), fixTrailingSlash); ok {
+				return out, true
+			}
+		}
+	}
+
+	if n.wildChild {
+		for _, child := range n.children {
+			switch child.nType {
+			case param:
+				segEnd := 0
+				for segEnd < len(path) && path[segEnd] != '/' {
+					segEnd++
+				}
+
+				consumed, ok := matchConstraint(child.constraint, path[:segEnd], len(child.children) > 0)
+				if !ok {
+					continue
+				}
+				end := consumed
+
+				next := append(append([]byte{}, ciPath...), path[:end]...)
+
+				if end < len(path) {
+					if out, ok := child.findCaseInsensitivePath(path[end:], next, fixTrailingSlash); ok {
+						return out, true
+					}
+					continue
+				}
+
+				if len(child.handlers) > 0 {
+					return next, true
+				}
+				if fixTrailingSlash {
+					for _, grandchild := range child.children {
+						if grandchild.path == "/" && len(grandchild.handlers) > 0 {
+							return append(next, '/'), true
+						}
+					}
+				}
+			case catchAll:
+				if _, ok := matchConstraint(child.constraint, path, false); !ok {
+					continue
+				}
+				if len(child.handlers) > 0 {
+					return append(append([]byte{}, ciPath...), path...), true
+				}
+			}
+		}
+	}
+
+	// Dead end: if only a trailing slash stands between path and a handler
+	// on this node, accept it when the caller is tolerating that.
+	if fixTrailingSlash && path == "/" && len(n.handlers) > 0 {
+		return ciPath, true
+	}
+
+	return ciPath, false
+}
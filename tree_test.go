@@ -2,6 +2,7 @@ package goflux
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -10,9 +11,18 @@ func testHandler(w http.ResponseWriter, r *http.Request, p Params) {
 	w.Write([]byte("test"))
 }
 
+// lookupTest calls n.getValue with a freshly allocated Params so existing
+// tests can keep asserting against a plain (handler, params, tsr) tuple
+// instead of juggling a *Params themselves.
+func lookupTest(n *node, path, method string) (HandlerFunc, Params, bool) {
+	var p Params
+	handler, tsr := n.getValue(path, method, &p)
+	return handler, p, tsr
+}
+
 func TestAddRoute_EmptyTree(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
+	tree.addRoute("/users", "GET", testHandler, false)
 
 	if tree.path != "/users" {
 		t.Errorf("Expected path '/users', got '%s'", tree.path)
@@ -25,8 +35,8 @@ func TestAddRoute_EmptyTree(t *testing.T) {
 
 func TestAddRoute_ExactMatch(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
-	tree.addRoute("/users", "POST", testHandler)
+	tree.addRoute("/users", "GET", testHandler, false)
+	tree.addRoute("/users", "POST", testHandler, false)
 
 	if len(tree.handlers) != 2 {
 		t.Errorf("Expected 2 handlers, got %d", len(tree.handlers))
@@ -49,15 +59,15 @@ func TestAddRoute_DuplicateMethod(t *testing.T) {
 	}()
 
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
-	tree.addRoute("/users", "GET", testHandler) // Should panic
+	tree.addRoute("/users", "GET", testHandler, false)
+	tree.addRoute("/users", "GET", testHandler, false) // Should panic
 }
 
 func TestAddRoute_MultipleRoutes(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
-	tree.addRoute("/about", "GET", testHandler)
-	tree.addRoute("/contact", "GET", testHandler)
+	tree.addRoute("/users", "GET", testHandler, false)
+	tree.addRoute("/about", "GET", testHandler, false)
+	tree.addRoute("/contact", "GET", testHandler, false)
 
 	// After adding different routes, tree should have split
 	if len(tree.children) == 0 {
@@ -67,8 +77,8 @@ func TestAddRoute_MultipleRoutes(t *testing.T) {
 
 func TestAddRoute_CommonPrefix(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
-	tree.addRoute("/user", "GET", testHandler)
+	tree.addRoute("/users", "GET", testHandler, false)
+	tree.addRoute("/user", "GET", testHandler, false)
 
 	// Should split into /user with child 's'
 	if tree.path != "/user" {
@@ -86,9 +96,9 @@ func TestAddRoute_CommonPrefix(t *testing.T) {
 
 func TestAddRoute_NestedRoutes(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users/profile", "GET", testHandler)
-	tree.addRoute("/users/settings", "GET", testHandler)
-	tree.addRoute("/users", "GET", testHandler)
+	tree.addRoute("/users/profile", "GET", testHandler, false)
+	tree.addRoute("/users/settings", "GET", testHandler, false)
+	tree.addRoute("/users", "GET", testHandler, false)
 
 	// Root should be /users
 	if tree.path != "/users" {
@@ -110,9 +120,9 @@ func TestAddRoute_NestedRoutes(t *testing.T) {
 
 func TestGetValue_ExactMatch(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
+	tree.addRoute("/users", "GET", testHandler, false)
 
-	handler, _ := tree.getValue("/users", "GET")
+	handler, _, _ := lookupTest(tree, "/users", "GET")
 	if handler == nil {
 		t.Error("Expected to find handler for /users GET")
 	}
@@ -120,9 +130,9 @@ func TestGetValue_ExactMatch(t *testing.T) {
 
 func TestGetValue_NotFound(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
+	tree.addRoute("/users", "GET", testHandler, false)
 
-	handler, _ := tree.getValue("/posts", "GET")
+	handler, _, _ := lookupTest(tree, "/posts", "GET")
 	if handler != nil {
 		t.Error("Expected no handler for /posts")
 	}
@@ -130,9 +140,9 @@ func TestGetValue_NotFound(t *testing.T) {
 
 func TestGetValue_WrongMethod(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
+	tree.addRoute("/users", "GET", testHandler, false)
 
-	handler, _ := tree.getValue("/users", "POST")
+	handler, _, _ := lookupTest(tree, "/users", "POST")
 	if handler != nil {
 		t.Error("Expected no handler for POST /users")
 	}
@@ -140,15 +150,15 @@ func TestGetValue_WrongMethod(t *testing.T) {
 
 func TestGetValue_NestedRoute(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users/profile", "GET", testHandler)
-	tree.addRoute("/users/settings", "GET", testHandler)
+	tree.addRoute("/users/profile", "GET", testHandler, false)
+	tree.addRoute("/users/settings", "GET", testHandler, false)
 
-	handler, _ := tree.getValue("/users/profile", "GET")
+	handler, _, _ := lookupTest(tree, "/users/profile", "GET")
 	if handler == nil {
 		t.Error("Expected to find handler for /users/profile")
 	}
 
-	handler, _ = tree.getValue("/users/settings", "GET")
+	handler, _, _ = lookupTest(tree, "/users/settings", "GET")
 	if handler == nil {
 		t.Error("Expected to find handler for /users/settings")
 	}
@@ -156,21 +166,21 @@ func TestGetValue_NestedRoute(t *testing.T) {
 
 func TestGetValue_MultipleRoutes(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users", "GET", testHandler)
-	tree.addRoute("/about", "GET", testHandler)
-	tree.addRoute("/contact", "GET", testHandler)
+	tree.addRoute("/users", "GET", testHandler, false)
+	tree.addRoute("/about", "GET", testHandler, false)
+	tree.addRoute("/contact", "GET", testHandler, false)
 
-	handler, _ := tree.getValue("/users", "GET")
+	handler, _, _ := lookupTest(tree, "/users", "GET")
 	if handler == nil {
 		t.Error("Expected to find /users")
 	}
 
-	handler, _ = tree.getValue("/about", "GET")
+	handler, _, _ = lookupTest(tree, "/about", "GET")
 	if handler == nil {
 		t.Error("Expected to find /about")
 	}
 
-	handler, _ = tree.getValue("/contact", "GET")
+	handler, _, _ = lookupTest(tree, "/contact", "GET")
 	if handler == nil {
 		t.Error("Expected to find /contact")
 	}
@@ -180,7 +190,7 @@ func TestGetValue_MultipleRoutes(t *testing.T) {
 
 func TestAddRoute_WithParam(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users/:id", "GET", testHandler)
+	tree.addRoute("/users/:id", "GET", testHandler, false)
 
 	// Should have wildChild flag set
 	if !tree.wildChild {
@@ -199,7 +209,7 @@ func TestAddRoute_WithParam(t *testing.T) {
 
 func TestAddRoute_WithCatchAll(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/files/*filepath", "GET", testHandler)
+	tree.addRoute("/files/*filepath", "GET", testHandler, false)
 
 	// Should have wildChild flag set
 	if !tree.wildChild {
@@ -218,9 +228,9 @@ func TestAddRoute_WithCatchAll(t *testing.T) {
 
 func TestGetValue_WithParam(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/users/:id", "GET", testHandler)
+	tree.addRoute("/users/:id", "GET", testHandler, false)
 
-	handler, params := tree.getValue("/users/123", "GET")
+	handler, params, _ := lookupTest(tree, "/users/123", "GET")
 	if handler == nil {
 		t.Error("Expected to find handler")
 	}
@@ -236,9 +246,9 @@ func TestGetValue_WithParam(t *testing.T) {
 
 func TestGetValue_WithMultipleParams(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/posts/:slug/comments/:commentId", "GET", testHandler)
+	tree.addRoute("/posts/:slug/comments/:commentId", "GET", testHandler, false)
 
-	handler, params := tree.getValue("/posts/hello-world/comments/456", "GET")
+	handler, params, _ := lookupTest(tree, "/posts/hello-world/comments/456", "GET")
 	if handler == nil {
 		t.Error("Expected to find handler")
 	}
@@ -258,9 +268,9 @@ func TestGetValue_WithMultipleParams(t *testing.T) {
 
 func TestGetValue_WithCatchAll(t *testing.T) {
 	tree := &node{}
-	tree.addRoute("/files/*filepath", "GET", testHandler)
+	tree.addRoute("/files/*filepath", "GET", testHandler, false)
 
-	handler, params := tree.getValue("/files/docs/readme.md", "GET")
+	handler, params, _ := lookupTest(tree, "/files/docs/readme.md", "GET")
 	if handler == nil {
 		t.Error("Expected to find handler")
 	}
@@ -273,3 +283,419 @@ func TestGetValue_WithCatchAll(t *testing.T) {
 		t.Errorf("Expected filepath=docs/readme.md, got %s", params.ByName("filepath"))
 	}
 }
+
+// Tests for static routes coexisting with param/catch-all siblings
+
+func TestGetValue_StaticBeforeParam(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users/me", "GET", testHandler, false)
+	tree.addRoute("/users/:id", "GET", testHandler, false)
+
+	handler, params, _ := lookupTest(tree, "/users/me", "GET")
+	if handler == nil {
+		t.Error("Expected static route to win over :id")
+	}
+	if len(params) != 0 {
+		t.Errorf("Expected no params for static match, got %v", params)
+	}
+
+	handler, params, _ = lookupTest(tree, "/users/123", "GET")
+	if handler == nil {
+		t.Error("Expected :id to match /users/123")
+	}
+	if params.ByName("id") != "123" {
+		t.Errorf("Expected id=123, got %s", params.ByName("id"))
+	}
+}
+
+func TestGetValue_ParamBeforeStatic(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users/:id", "GET", testHandler, false)
+	tree.addRoute("/users/me", "GET", testHandler, false)
+
+	// Registration order shouldn't matter: static still wins for an exact match
+	handler, params, _ := lookupTest(tree, "/users/me", "GET")
+	if handler == nil {
+		t.Error("Expected static route to win over :id regardless of registration order")
+	}
+	if len(params) != 0 {
+		t.Errorf("Expected no params for static match, got %v", params)
+	}
+
+	handler, params, _ = lookupTest(tree, "/users/123", "GET")
+	if handler == nil {
+		t.Error("Expected :id to match /users/123")
+	}
+	if params.ByName("id") != "123" {
+		t.Errorf("Expected id=123, got %s", params.ByName("id"))
+	}
+}
+
+func TestGetValue_StaticAndCatchAllSiblings(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/files/*path", "GET", testHandler, false)
+	tree.addRoute("/files/index.html", "GET", testHandler, false)
+
+	handler, params, _ := lookupTest(tree, "/files/index.html", "GET")
+	if handler == nil {
+		t.Error("Expected static route to win over *path")
+	}
+	if len(params) != 0 {
+		t.Errorf("Expected no params for static match, got %v", params)
+	}
+
+	handler, params, _ = lookupTest(tree, "/files/docs/readme.md", "GET")
+	if handler == nil {
+		t.Error("Expected *path to catch unmatched files")
+	}
+	if params.ByName("path") != "docs/readme.md" {
+		t.Errorf("Expected path=docs/readme.md, got %s", params.ByName("path"))
+	}
+}
+
+func TestGetValue_CatchAllFallbackForUnmatchedRoutes(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/user/groups", "GET", testHandler, false)
+	tree.addRoute("/*action", "GET", testHandler, false)
+
+	handler, _, _ := lookupTest(tree, "/user/groups", "GET")
+	if handler == nil {
+		t.Error("Expected /user/groups to resolve to its own static route")
+	}
+
+	handler, params, _ := lookupTest(tree, "/anything/else", "GET")
+	if handler == nil {
+		t.Error("Expected *action to serve as a fallback for unmatched paths")
+	}
+	if params.ByName("action") != "anything/else" {
+		t.Errorf("Expected action=anything/else, got %s", params.ByName("action"))
+	}
+}
+
+func TestGetValue_NestedParamAndCatchAllSibling(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/user/:name/*action", "GET", testHandler, false)
+	tree.addRoute("/user/groups", "GET", testHandler, false)
+
+	handler, _, _ := lookupTest(tree, "/user/groups", "GET")
+	if handler == nil {
+		t.Error("Expected /user/groups to resolve to its own static route")
+	}
+
+	handler, params, _ := lookupTest(tree, "/user/gopher/send-message", "GET")
+	if handler == nil {
+		t.Error("Expected /user/:name/*action to match")
+	}
+	if params.ByName("name") != "gopher" {
+		t.Errorf("Expected name=gopher, got %s", params.ByName("name"))
+	}
+	if params.ByName("action") != "send-message" {
+		t.Errorf("Expected action=send-message, got %s", params.ByName("action"))
+	}
+}
+
+// Tests for mutable route registration
+
+func TestAddRoute_MutableReplacesStaticHandler(t *testing.T) {
+	first := func(w http.ResponseWriter, r *http.Request, p Params) { w.Write([]byte("first")) }
+	second := func(w http.ResponseWriter, r *http.Request, p Params) { w.Write([]byte("second")) }
+
+	tree := &node{}
+	tree.addRoute("/users", "GET", first, false)
+	tree.addRoute("/users", "GET", second, true) // should replace, not panic
+
+	handler, _, _ := lookupTest(tree, "/users", "GET")
+	if handler == nil {
+		t.Fatal("Expected to find handler for /users GET")
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, nil, nil)
+	if rec.Body.String() != "second" {
+		t.Errorf("Expected replaced handler to run, got body %q", rec.Body.String())
+	}
+}
+
+func TestAddRoute_MutableReplacesWildcardHandler(t *testing.T) {
+	first := func(w http.ResponseWriter, r *http.Request, p Params) { w.Write([]byte("first")) }
+	second := func(w http.ResponseWriter, r *http.Request, p Params) { w.Write([]byte("second")) }
+
+	tree := &node{}
+	tree.addRoute("/users/:id", "GET", first, false)
+	tree.addRoute("/users/:id", "GET", second, true) // should replace, not panic
+
+	handler, _, _ := lookupTest(tree, "/users/42", "GET")
+	if handler == nil {
+		t.Fatal("Expected to find handler for /users/:id GET")
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, nil, nil)
+	if rec.Body.String() != "second" {
+		t.Errorf("Expected replaced handler to run, got body %q", rec.Body.String())
+	}
+}
+
+func TestAddRoute_NotMutableStillPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for duplicate route registration without mutable")
+		}
+	}()
+
+	tree := &node{}
+	tree.addRoute("/users", "GET", testHandler, false)
+	tree.addRoute("/users", "GET", testHandler, false) // Should panic
+}
+
+// Tests for trailing-slash redirect recommendations
+
+func TestGetValue_TSRMissingTrailingSlash(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users/", "GET", testHandler, false)
+
+	handler, _, tsr := lookupTest(tree, "/users", "GET")
+	if handler != nil {
+		t.Error("Expected no exact handler for /users")
+	}
+	if !tsr {
+		t.Error("Expected tsr=true since /users/ is registered")
+	}
+}
+
+func TestGetValue_TSRExtraTrailingSlash(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users", "GET", testHandler, false)
+
+	handler, _, tsr := lookupTest(tree, "/users/", "GET")
+	if handler != nil {
+		t.Error("Expected no exact handler for /users/")
+	}
+	if !tsr {
+		t.Error("Expected tsr=true since /users is registered")
+	}
+}
+
+func TestGetValue_NoTSRWhenNothingMatches(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users", "GET", testHandler, false)
+
+	handler, _, tsr := lookupTest(tree, "/posts", "GET")
+	if handler != nil {
+		t.Error("Expected no handler for /posts")
+	}
+	if tsr {
+		t.Error("Expected tsr=false since neither /posts nor /posts/ is registered")
+	}
+}
+
+// Tests for CleanPath
+
+func TestCleanPath(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"", "/"},
+		{"/", "/"},
+		{"//users", "/users"},
+		{"/users//123", "/users/123"},
+		{"/users/./123", "/users/123"},
+		{"/users/123/", "/users/123/"},
+		{"/users/other/../123", "/users/123"},
+		{"/../users", "/users"},
+	}
+
+	for _, c := range cases {
+		if got := CleanPath(c.in); got != c.want {
+			t.Errorf("CleanPath(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+// Tests for FindCaseInsensitivePath
+
+func TestFindCaseInsensitivePath_StaticRoute(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/Users/profile", "GET", testHandler, false)
+
+	fixed, found := tree.FindCaseInsensitivePath("/users/PROFILE", false)
+	if !found {
+		t.Fatal("Expected case-insensitive match")
+	}
+	if fixed != "/Users/profile" {
+		t.Errorf("Expected /Users/profile, got %s", fixed)
+	}
+}
+
+func TestFindCaseInsensitivePath_WithParamAndTrailingSlash(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/Users/:id", "GET", testHandler, false)
+
+	fixed, found := tree.FindCaseInsensitivePath("/users/123/", true)
+	if !found {
+		t.Fatal("Expected case-insensitive match with trailing slash tolerated")
+	}
+	if fixed != "/Users/123" {
+		t.Errorf("Expected /Users/123, got %s", fixed)
+	}
+}
+
+func TestFindCaseInsensitivePath_NoTrailingSlashToleranceFails(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/Users/:id", "GET", testHandler, false)
+
+	_, found := tree.FindCaseInsensitivePath("/users/123/", false)
+	if found {
+		t.Error("Expected no match when trailing slash tolerance is disabled")
+	}
+}
+
+func TestFindCaseInsensitivePath_NotFound(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/Users/profile", "GET", testHandler, false)
+
+	_, found := tree.FindCaseInsensitivePath("/users/settings", false)
+	if found {
+		t.Error("Expected no match for an unregistered path")
+	}
+}
+
+// Tests for priority-ordered children and maxParams tracking
+
+func TestAddRoute_ChildrenSortedByPriority(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users/settings", "GET", testHandler, false)
+	tree.addRoute("/users/profile", "GET", testHandler, false)
+
+	// /users/profile is registered again five more times, each bumping its
+	// priority, so it should end up first among /users's children once its
+	// priority overtakes settings'. Lookups alone leave priority unchanged.
+	for i := 0; i < 5; i++ {
+		lookupTest(tree, "/users/profile", "GET")
+		tree.addRoute("/users/profile", "GET", testHandler, true)
+	}
+
+	if tree.children[0].priority < tree.children[1].priority {
+		t.Errorf("Expected children sorted by descending priority, got priorities %d, %d",
+			tree.children[0].priority, tree.children[1].priority)
+	}
+}
+
+func TestAddRoute_MaxParamsTracksDeepestRoute(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/posts/:slug/comments/:commentId", "GET", testHandler, false)
+
+	if tree.maxParams != 2 {
+		t.Errorf("Expected root maxParams=2, got %d", tree.maxParams)
+	}
+
+	tree.addRoute("/posts/:slug/comments/:commentId/replies/:replyId", "GET", testHandler, false)
+
+	if tree.maxParams != 3 {
+		t.Errorf("Expected root maxParams=3 after adding a deeper route, got %d", tree.maxParams)
+	}
+}
+
+func TestAddRoute_MaxParamsUnaffectedBySiblingRoutes(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users/:id", "GET", testHandler, false)
+	tree.addRoute("/about", "GET", testHandler, false)
+
+	if tree.maxParams != 1 {
+		t.Errorf("Expected root maxParams=1, got %d", tree.maxParams)
+	}
+}
+
+// Benchmarks for zero-allocation lookups against a pre-sized Params slice
+
+func BenchmarkGetValue_StaticRoute(b *testing.B) {
+	tree := &node{}
+	tree.addRoute("/users/profile", "GET", testHandler, false)
+
+	var p Params
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.getValue("/users/profile", "GET", &p)
+	}
+}
+
+func BenchmarkGetValue_ParamRoute(b *testing.B) {
+	tree := &node{}
+	tree.addRoute("/posts/:slug/comments/:commentId", "GET", testHandler, false)
+
+	p := make(Params, 0, tree.maxParams)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p = p[:0]
+		tree.getValue("/posts/hello-world/comments/456", "GET", &p)
+	}
+}
+
+// Tests for regex-constrained parameters
+
+func TestAddRoute_InvalidConstraintPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for invalid regex constraint")
+		}
+	}()
+
+	tree := &node{}
+	tree.addRoute("/users/:id(", "GET", testHandler, false)
+}
+
+func TestGetValue_ConstrainedParamDisambiguatesStaticSibling(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users/me", "GET", testHandler, false)
+	tree.addRoute("/users/:id(\\d+)", "GET", testHandler, false)
+
+	handler, _, _ := lookupTest(tree, "/users/me", "GET")
+	if handler == nil {
+		t.Error("Expected static /users/me to still win over the constrained param")
+	}
+
+	handler, params, _ := lookupTest(tree, "/users/123", "GET")
+	if handler == nil {
+		t.Error("Expected :id(\\d+) to match /users/123")
+	}
+	if params.ByName("id") != "123" {
+		t.Errorf("Expected id=123, got %s", params.ByName("id"))
+	}
+}
+
+func TestGetValue_ConstrainedParamRejectsNonMatchingValue(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/users/:id(\\d+)", "GET", testHandler, false)
+
+	handler, _, _ := lookupTest(tree, "/users/abc", "GET")
+	if handler != nil {
+		t.Error("Expected no match for a non-numeric id against :id(\\d+)")
+	}
+
+	handler, params, _ := lookupTest(tree, "/users/42", "GET")
+	if handler == nil {
+		t.Error("Expected :id(\\d+) to match /users/42")
+	}
+	if params.ByName("id") != "42" {
+		t.Errorf("Expected id=42, got %s", params.ByName("id"))
+	}
+}
+
+func TestGetValue_ChainedConstrainedAndPlainParams(t *testing.T) {
+	tree := &node{}
+	tree.addRoute("/files/:name([a-z]+).:ext", "GET", testHandler, false)
+
+	handler, params, _ := lookupTest(tree, "/files/readme.md", "GET")
+	if handler == nil {
+		t.Error("Expected /files/:name([a-z]+).:ext to match /files/readme.md")
+	}
+	if params.ByName("name") != "readme" {
+		t.Errorf("Expected name=readme, got %s", params.ByName("name"))
+	}
+	if params.ByName("ext") != "md" {
+		t.Errorf("Expected ext=md, got %s", params.ByName("ext"))
+	}
+
+	handler, _, _ = lookupTest(tree, "/files/readme123.md", "GET")
+	if handler != nil {
+		t.Error("Expected no match when name contains digits, violating [a-z]+")
+	}
+}